@@ -0,0 +1,115 @@
+package hftorderbook
+
+// ArbConfig bounds an arbitrage scan between two books.
+type ArbConfig struct {
+	// MinSpread is the minimum crossing spread (in price units) worth
+	// reporting; levels narrower than this stop the scan.
+	MinSpread int64
+	// MaxNotional caps the total notional (valued at the bid leg's price)
+	// matched across an opportunity. Zero means unbounded.
+	MaxNotional int64
+}
+
+// ArbFill is one matched level pair within an ArbOpportunity.
+type ArbFill struct {
+	PriceA int64
+	PriceB int64
+	Qty    int64
+}
+
+// ArbOpportunity is a crossing run between two books: buy on the venue
+// quoting the lower ask, sell on the venue quoting the higher bid.
+type ArbOpportunity struct {
+	Fills       []ArbFill
+	Qty         int64
+	VWAPA       int64
+	VWAPB       int64
+	GrossSpread int64
+}
+
+// DetectArbitrage walks a's bids against b's asks, and b's bids against a's
+// asks, reporting any crossing runs (a's best bid above b's best ask, or
+// vice-versa) up to cfg.MaxNotional, stopping once the spread narrows below
+// cfg.MinSpread or flips.
+func DetectArbitrage(a, b *Orderbook, cfg ArbConfig) []ArbOpportunity {
+	opportunities := make([]ArbOpportunity, 0, 2)
+	if opp := scanCrossing(a.Bids, b.Asks, cfg); opp != nil {
+		opportunities = append(opportunities, *opp)
+	}
+	if opp := scanCrossing(b.Bids, a.Asks, cfg); opp != nil {
+		opportunities = append(opportunities, *opp)
+	}
+	return opportunities
+}
+
+// scanCrossing matches bidTree's best-down against askTree's best-up while
+// the spread holds, returning the accumulated opportunity or nil if the two
+// books don't cross.
+func scanCrossing(bidTree, askTree *redBlackBST, cfg ArbConfig) *ArbOpportunity {
+	if bidTree == nil || askTree == nil || bidTree.IsEmpty() || askTree.IsEmpty() {
+		return nil
+	}
+
+	bidNode := bidTree.MaxPointer()
+	askNode := askTree.MinPointer()
+
+	var fills []ArbFill
+	var qty, notionalA, notionalB int64
+
+	for bidNode != nil && askNode != nil {
+		bidLimit := bidNode.Value
+		askLimit := askNode.Value
+
+		spread := bidLimit.Price - askLimit.Price
+		if spread < cfg.MinSpread {
+			break
+		}
+
+		avail := bidLimit.totalVolume
+		if askLimit.totalVolume < avail {
+			avail = askLimit.totalVolume
+		}
+
+		if cfg.MaxNotional > 0 {
+			headroom := cfg.MaxNotional - notionalA
+			if headroom <= 0 {
+				break
+			}
+			if maxQty := headroom / bidLimit.Price; avail > maxQty {
+				avail = maxQty
+			}
+		}
+		if avail <= 0 {
+			break
+		}
+
+		fills = append(fills, ArbFill{PriceA: bidLimit.Price, PriceB: askLimit.Price, Qty: avail})
+		qty += avail
+		notionalA += avail * bidLimit.Price
+		notionalB += avail * askLimit.Price
+
+		exhaustedBid := avail == bidLimit.totalVolume
+		exhaustedAsk := avail == askLimit.totalVolume
+		if exhaustedBid {
+			bidNode = bidNode.Prev
+		}
+		if exhaustedAsk {
+			askNode = askNode.Next
+		}
+		if !exhaustedBid && !exhaustedAsk {
+			// capped by MaxNotional short of either level's full size
+			break
+		}
+	}
+
+	if len(fills) == 0 {
+		return nil
+	}
+	return &ArbOpportunity{
+		Fills:       fills,
+		Qty:         qty,
+		VWAPA:       notionalA / qty,
+		VWAPB:       notionalB / qty,
+		GrossSpread: notionalA - notionalB,
+	}
+}
@@ -0,0 +1,46 @@
+package hftorderbook
+
+import "testing"
+
+func TestDetectArbitrageFindsCrossingRun(t *testing.T) {
+	// a's second bid (90) is well below b's second ask (101) so the run
+	// stops after the first level instead of also crossing there.
+	a := newTestBook([]int64{105, 90}, []int64{108}, 10, 1)
+	b := newTestBook([]int64{95}, []int64{100, 101}, 10, 1)
+
+	opportunities := DetectArbitrage(a, b, ArbConfig{MinSpread: 1})
+	if len(opportunities) != 1 {
+		t.Fatalf("expected exactly one crossing opportunity (a's bid 105 > b's ask 100), got %d: %+v", len(opportunities), opportunities)
+	}
+
+	opp := opportunities[0]
+	if opp.Qty != 10 {
+		t.Fatalf("expected matched qty 10 (single level on each side), got %d", opp.Qty)
+	}
+	if opp.Fills[0].PriceA != 105 || opp.Fills[0].PriceB != 100 {
+		t.Fatalf("expected first fill at (105,100), got %+v", opp.Fills[0])
+	}
+}
+
+func TestDetectArbitrageNoneWhenSpreadTooNarrow(t *testing.T) {
+	a := newTestBook([]int64{100}, []int64{105}, 10, 1)
+	b := newTestBook([]int64{95}, []int64{101}, 10, 1)
+
+	opportunities := DetectArbitrage(a, b, ArbConfig{MinSpread: 100})
+	if len(opportunities) != 0 {
+		t.Fatalf("expected no opportunities above MinSpread, got %+v", opportunities)
+	}
+}
+
+func TestDetectArbitrageRespectsMaxNotional(t *testing.T) {
+	a := newTestBook([]int64{105}, nil, 100, 1)
+	b := newTestBook(nil, []int64{100}, 100, 1)
+
+	opportunities := DetectArbitrage(a, b, ArbConfig{MinSpread: 1, MaxNotional: 105 * 10})
+	if len(opportunities) != 1 {
+		t.Fatalf("expected one opportunity, got %+v", opportunities)
+	}
+	if opportunities[0].Qty > 10 {
+		t.Fatalf("expected qty capped near MaxNotional/price, got %d", opportunities[0].Qty)
+	}
+}
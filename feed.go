@@ -0,0 +1,130 @@
+package hftorderbook
+
+import "errors"
+
+// UpdateType distinguishes the kind of change an incremental Update carries.
+type UpdateType int
+
+const (
+	UpdateAdd UpdateType = iota
+	UpdateModify
+	UpdateDelete
+)
+
+// ErrSeqGap is returned by ApplyUpdate when an update arrives out of
+// sequence, meaning the caller must request a fresh snapshot.
+var ErrSeqGap = errors.New("hftorderbook: sequence gap, resync required")
+
+// Update is one incremental depth change from a feed, carrying the sequence
+// number needed to detect gaps.
+type Update struct {
+	Seq     uint64
+	Type    UpdateType
+	Side    bool
+	Price   int64
+	Volume  int64
+	OrderID int
+}
+
+// PendingBuffer queues updates that arrive before a snapshot has been
+// installed, so they can be replayed once the book has a baseline.
+type PendingBuffer struct {
+	updates []Update
+}
+
+func NewPendingBuffer() *PendingBuffer {
+	return &PendingBuffer{}
+}
+
+func (this *PendingBuffer) Push(u Update) {
+	this.updates = append(this.updates, u)
+}
+
+// Drain returns and clears the updates with Seq greater than snapshotSeq, in
+// arrival order.
+func (this *PendingBuffer) Drain(snapshotSeq uint64) []Update {
+	replay := make([]Update, 0, len(this.updates))
+	for _, u := range this.updates {
+		if u.Seq > snapshotSeq {
+			replay = append(replay, u)
+		}
+	}
+	this.updates = nil
+	return replay
+}
+
+// snapshotOrderIDBase anchors a strictly-decreasing range of synthetic order
+// IDs for the depth levels ApplySnapshot fabricates, so they never collide
+// with real (non-negative) order IDs or with each other.
+const snapshotOrderIDBase = -1
+
+// ApplySnapshot atomically clears and rebuilds the book from bids/asks depth
+// levels, installs seq as the baseline, and replays any updates buffered in
+// Pending that arrived ahead of this snapshot. It returns the first error
+// hit replaying that buffer (e.g. ErrSeqGap if the buffer itself has a hole),
+// stopping the replay at that point; the snapshot and any updates replayed
+// before the error remain applied, but the caller must treat this the same
+// as any other ErrSeqGap and request a fresh snapshot.
+func (this *Orderbook) ApplySnapshot(seq uint64, bids, asks []OrderDepth) error {
+	pending := this.Pending
+	onFill := this.OnFill
+	fresh := NewOrderbook()
+	*this = fresh
+	this.Pending = pending
+	this.OnFill = onFill
+
+	nextID := snapshotOrderIDBase
+	for _, depth := range bids {
+		this.Add(depth.Price, &Order{Id: nextID, Price: depth.Price, Volume: depth.Volume, BidOrAsk: true})
+		nextID--
+	}
+	for _, depth := range asks {
+		this.Add(depth.Price, &Order{Id: nextID, Price: depth.Price, Volume: depth.Volume, BidOrAsk: false})
+		nextID--
+	}
+
+	this.lastSeq = seq
+	this.snapshotInstalled = true
+
+	if this.Pending == nil {
+		return nil
+	}
+	for _, u := range this.Pending.Drain(seq) {
+		if err := this.ApplyUpdate(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyUpdate applies a single incremental update. If no snapshot has been
+// installed yet, it is buffered in Pending instead. Updates that don't
+// immediately follow lastSeq are rejected with ErrSeqGap so the caller can
+// request a fresh snapshot.
+func (this *Orderbook) ApplyUpdate(u Update) error {
+	if !this.snapshotInstalled {
+		if this.Pending == nil {
+			this.Pending = NewPendingBuffer()
+		}
+		this.Pending.Push(u)
+		return nil
+	}
+
+	if u.Seq != this.lastSeq+1 {
+		return ErrSeqGap
+	}
+
+	switch u.Type {
+	case UpdateAdd:
+		this.Add(u.Price, &Order{Id: u.OrderID, Price: u.Price, Volume: u.Volume, BidOrAsk: u.Side})
+	case UpdateModify:
+		this.Modify(u.Price, &Order{Id: u.OrderID, Price: u.Price, Volume: u.Volume, BidOrAsk: u.Side})
+	case UpdateDelete:
+		if order, ok := this.IdToOrderMap[u.OrderID]; ok {
+			this.Cancel(order)
+		}
+	}
+
+	this.lastSeq = u.Seq
+	return nil
+}
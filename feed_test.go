@@ -0,0 +1,113 @@
+package hftorderbook
+
+import "testing"
+
+func TestApplySnapshotAssignsUniqueOrderIDs(t *testing.T) {
+	var book Orderbook
+	if err := book.ApplySnapshot(1, []OrderDepth{
+		{Price: 99, Volume: 10},
+		{Price: 98, Volume: 20},
+	}, []OrderDepth{
+		{Price: 101, Volume: 5},
+		{Price: 102, Volume: 7},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := len(book.IdToOrderMap); got != 4 {
+		t.Fatalf("expected 4 distinct synthetic orders in IdToOrderMap, got %d", got)
+	}
+	if got := book.GetVolumeAtBidLimit(99); got != 10 {
+		t.Fatalf("bid limit 99: expected volume 10, got %d", got)
+	}
+	if got := book.GetVolumeAtBidLimit(98); got != 20 {
+		t.Fatalf("bid limit 98: expected volume 20, got %d", got)
+	}
+}
+
+func TestApplySnapshotPreservesOnFill(t *testing.T) {
+	var book Orderbook
+	var fills int
+	book.OnFill = func(Fill) { fills++ }
+
+	if err := book.ApplySnapshot(1, []OrderDepth{{Price: 99, Volume: 10}}, []OrderDepth{{Price: 101, Volume: 10}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if book.OnFill == nil {
+		t.Fatal("OnFill should survive ApplySnapshot")
+	}
+
+	taker := &Order{Id: 1000, Price: 101, Volume: 10, BidOrAsk: true}
+	if _, _, err := book.Match(taker, IOC); err != nil {
+		t.Fatalf("Match returned error: %v", err)
+	}
+	if fills != 1 {
+		t.Fatalf("expected OnFill to fire once, got %d", fills)
+	}
+}
+
+func TestApplyUpdateRejectsSequenceGap(t *testing.T) {
+	var book Orderbook
+	if err := book.ApplySnapshot(5, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := book.ApplyUpdate(Update{Seq: 7, Type: UpdateAdd, Side: true, Price: 100, Volume: 1, OrderID: 1})
+	if err != ErrSeqGap {
+		t.Fatalf("expected ErrSeqGap, got %v", err)
+	}
+}
+
+func TestApplyUpdateBuffersBeforeSnapshotAndReplaysInOrder(t *testing.T) {
+	var book Orderbook
+
+	if err := book.ApplyUpdate(Update{Seq: 6, Type: UpdateAdd, Side: true, Price: 100, Volume: 3, OrderID: 11}); err != nil {
+		t.Fatalf("buffering before snapshot should not error: %v", err)
+	}
+	if err := book.ApplyUpdate(Update{Seq: 7, Type: UpdateAdd, Side: true, Price: 101, Volume: 4, OrderID: 12}); err != nil {
+		t.Fatalf("buffering before snapshot should not error: %v", err)
+	}
+
+	if err := book.ApplySnapshot(5, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := book.GetVolumeAtBidLimit(100); got != 3 {
+		t.Fatalf("expected replayed update at 100 to add volume 3, got %d", got)
+	}
+	if got := book.GetVolumeAtBidLimit(101); got != 4 {
+		t.Fatalf("expected replayed update at 101 to add volume 4, got %d", got)
+	}
+	if book.lastSeq != 7 {
+		t.Fatalf("expected lastSeq advanced to 7 after replay, got %d", book.lastSeq)
+	}
+}
+
+func TestApplySnapshotStopsReplayOnGapInPendingBuffer(t *testing.T) {
+	var book Orderbook
+
+	if err := book.ApplyUpdate(Update{Seq: 6, Type: UpdateAdd, Side: true, Price: 100, Volume: 3, OrderID: 11}); err != nil {
+		t.Fatalf("buffering before snapshot should not error: %v", err)
+	}
+	// Seq 7 is missing: the pending buffer itself has a gap.
+	if err := book.ApplyUpdate(Update{Seq: 8, Type: UpdateAdd, Side: true, Price: 101, Volume: 4, OrderID: 12}); err != nil {
+		t.Fatalf("buffering before snapshot should not error: %v", err)
+	}
+
+	err := book.ApplySnapshot(5, nil, nil)
+	if err != ErrSeqGap {
+		t.Fatalf("expected ErrSeqGap surfaced from the pending-buffer gap, got %v", err)
+	}
+
+	// The update before the gap (seq 6) should have replayed; the one after
+	// the gap (seq 8) should not have.
+	if got := book.GetVolumeAtBidLimit(100); got != 3 {
+		t.Fatalf("expected update before the gap to have replayed, got volume %d", got)
+	}
+	if got := book.GetVolumeAtBidLimit(101); got != 0 {
+		t.Fatalf("expected update after the gap to not have replayed, got volume %d", got)
+	}
+	if book.lastSeq != 6 {
+		t.Fatalf("expected lastSeq to stop at 6 (last update applied before the gap), got %d", book.lastSeq)
+	}
+}
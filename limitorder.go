@@ -0,0 +1,82 @@
+package hftorderbook
+
+// LimitOrder is the FIFO queue of resting orders at a single price level.
+// totalVolume is kept in sync with the queue by Enqueue, Delete and Fill, so
+// callers never need to re-sum it from the individual orders.
+type LimitOrder struct {
+	Price       int64
+	totalVolume int64
+	count       int
+	head, tail  *Order
+}
+
+// NewLimitOrder returns an empty level at price, ready for Enqueue. price is
+// truncated to int64; the book's pool calls it with 0.0 to construct the
+// zero-valued instance reused across prices.
+func NewLimitOrder(price float64) LimitOrder {
+	return LimitOrder{Price: int64(price)}
+}
+
+// Enqueue appends o to the back of the FIFO queue, taking over as its Limit.
+func (this *LimitOrder) Enqueue(o *Order) {
+	o.Limit = this
+	o.prev = this.tail
+	o.next = nil
+	if this.tail != nil {
+		this.tail.next = o
+	} else {
+		this.head = o
+	}
+	this.tail = o
+	this.totalVolume += o.Volume
+	this.count++
+}
+
+// Delete removes o from the queue, wherever it sits, and unlinks it.
+func (this *LimitOrder) Delete(o *Order) {
+	if o.prev != nil {
+		o.prev.next = o.next
+	} else if this.head == o {
+		this.head = o.next
+	}
+	if o.next != nil {
+		o.next.prev = o.prev
+	} else if this.tail == o {
+		this.tail = o.prev
+	}
+	this.totalVolume -= o.Volume
+	this.count--
+	o.next, o.prev, o.Limit = nil, nil, nil
+}
+
+// Fill consumes qty from o, which must be the current Front(), keeping
+// totalVolume in sync. It does not dequeue o even if this brings its Volume
+// to zero; callers check for that and call Delete themselves, same as a full
+// cancel.
+func (this *LimitOrder) Fill(o *Order, qty int64) {
+	o.Volume -= qty
+	this.totalVolume -= qty
+}
+
+// Front returns the oldest resting order at this level, or nil if empty.
+func (this *LimitOrder) Front() *Order {
+	return this.head
+}
+
+// Size returns the number of orders resting at this level.
+func (this *LimitOrder) Size() int {
+	return this.count
+}
+
+// TotalVolume returns the summed volume of every order resting at this level.
+func (this *LimitOrder) TotalVolume() int64 {
+	return this.totalVolume
+}
+
+// Clear empties the level without individually unlinking its orders, for
+// callers that are discarding the whole level (e.g. DeleteBidLimit).
+func (this *LimitOrder) Clear() {
+	this.head, this.tail = nil, nil
+	this.totalVolume = 0
+	this.count = 0
+}
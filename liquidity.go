@@ -0,0 +1,214 @@
+package hftorderbook
+
+import "math"
+
+// OrderSource exposes a price ladder one level at a time: NextLevel returns
+// the level beyond prevPrice (away from the touch) on the requested side,
+// or ok=false once the source is exhausted. Passing prevPrice=0 starts from
+// the best level.
+type OrderSource interface {
+	NextLevel(prevPrice int64, isBuy bool) (price, qty int64, ok bool)
+}
+
+// NextLevel adapts Orderbook to OrderSource: isBuy selects the bid side
+// (true) or ask side (false), matching the isBuyDepth convention used by
+// GetNBestBid/GetNBestOffer.
+func (this *Orderbook) NextLevel(prevPrice int64, isBuy bool) (price, qty int64, ok bool) {
+	var tree *redBlackBST
+	if isBuy {
+		tree = this.Bids
+	} else {
+		tree = this.Asks
+	}
+	if tree == nil || tree.IsEmpty() {
+		return 0, 0, false
+	}
+
+	// walkStart/walkNext take the taker's side, so the walk that starts at
+	// this side's best level is the one a taker on the other side (!isBuy)
+	// makes.
+	var nodePointer *nodeRedBlack
+	if prevPrice == 0 {
+		nodePointer = this.walkStart(!isBuy, tree)
+	} else {
+		nodePointer = this.walkStart(!isBuy, tree)
+		for nodePointer != nil && nodePointer.Value.Price != prevPrice {
+			nodePointer = this.walkNext(!isBuy, nodePointer)
+		}
+		if nodePointer == nil {
+			return 0, 0, false
+		}
+		nodePointer = this.walkNext(!isBuy, nodePointer)
+	}
+
+	if nodePointer == nil {
+		return 0, 0, false
+	}
+	limit := nodePointer.Value
+	return limit.Price, limit.totalVolume, true
+}
+
+// ConstantProductPool synthesizes a bid/ask ladder from an x*y=k pool, so it
+// can be merged with a real book via MergeSources. Fee is in basis points
+// charged against the taker.
+type ConstantProductPool struct {
+	RX, RY   int64
+	Fee      int64
+	TickSize int64
+
+	// bidIdx/askIdx are the raw tick indices already handed out on each
+	// side. They're tracked here rather than re-derived from the
+	// fee-adjusted price NextLevel returns, since that price can't be
+	// inverted back to the tick it came from.
+	bidIdx int64
+	askIdx int64
+}
+
+// spot is the pool's instantaneous price (RY per unit RX) at zero size.
+func (this *ConstantProductPool) spot() float64 {
+	return float64(this.RY) / float64(this.RX)
+}
+
+// cumulativeDy returns the total Y moved (dy) once the pool's marginal price
+// has walked to price, per (RX+dx)(RY-dy)=RX*RY solved for the reserve that
+// yields that marginal price.
+func (this *ConstantProductPool) cumulativeDy(price float64) float64 {
+	rx, ry := float64(this.RX), float64(this.RY)
+	newRX := math.Sqrt(rx * ry / price)
+	newRY := rx * ry / newRX
+	return ry - newRY
+}
+
+// NextLevel synthesizes the k-th tick above (ask) or below (bid) the pool's
+// spot, reporting the incremental quantity available at that tick versus
+// the one before it. The raw tick index advances from internal state
+// (bidIdx/askIdx), not from prevPrice, since prevPrice is the fee-adjusted
+// price previously returned and can't be mapped back to its tick; passing
+// prevPrice=0 restarts that side's ladder from tick 1.
+func (this *ConstantProductPool) NextLevel(prevPrice int64, isBuy bool) (price, qty int64, ok bool) {
+	if this.RX <= 0 || this.RY <= 0 || this.TickSize <= 0 {
+		return 0, 0, false
+	}
+
+	spot := this.spot()
+	var k int64
+	if isBuy {
+		if prevPrice == 0 {
+			this.bidIdx = 0
+		}
+		this.bidIdx++
+		k = this.bidIdx
+	} else {
+		if prevPrice == 0 {
+			this.askIdx = 0
+		}
+		this.askIdx++
+		k = this.askIdx
+	}
+
+	var tickPrice float64
+	if isBuy {
+		tickPrice = spot - float64(k)*float64(this.TickSize)
+	} else {
+		tickPrice = spot + float64(k)*float64(this.TickSize)
+	}
+	if tickPrice <= 0 {
+		return 0, 0, false
+	}
+
+	feeAdj := 1 + float64(this.Fee)/10000
+	if isBuy {
+		feeAdj = 1 - float64(this.Fee)/10000
+	}
+	effectivePrice := tickPrice * feeAdj
+
+	dyHere := this.cumulativeDy(tickPrice)
+	dyPrev := 0.0
+	if k > 1 {
+		var prevTick float64
+		if isBuy {
+			prevTick = spot - float64(k-1)*float64(this.TickSize)
+		} else {
+			prevTick = spot + float64(k-1)*float64(this.TickSize)
+		}
+		dyPrev = this.cumulativeDy(prevTick)
+	}
+
+	levelQty := int64(math.Abs(dyHere - dyPrev))
+	if levelQty <= 0 {
+		return 0, 0, false
+	}
+	return int64(effectivePrice), levelQty, true
+}
+
+// MergeSources returns an OrderSource that, at each call, advances only the
+// child source that produced the previously returned level and returns the
+// best price across all children (max for bids, min for asks) — a heap
+// merge of independent ladders into one.
+func MergeSources(sources ...OrderSource) OrderSource {
+	return &mergedSource{
+		sources: sources,
+		peeked:  [2][]*peekedLevel{make([]*peekedLevel, len(sources)), make([]*peekedLevel, len(sources))},
+		cursor:  [2][]int64{make([]int64, len(sources)), make([]int64, len(sources))},
+	}
+}
+
+type peekedLevel struct {
+	price, qty int64
+	ok         bool
+}
+
+type mergedSource struct {
+	sources []OrderSource
+	peeked  [2][]*peekedLevel
+	cursor  [2][]int64
+}
+
+func sideIndex(isBuy bool) int {
+	if isBuy {
+		return 0
+	}
+	return 1
+}
+
+func (this *mergedSource) NextLevel(prevPrice int64, isBuy bool) (price, qty int64, ok bool) {
+	side := sideIndex(isBuy)
+	if prevPrice == 0 {
+		for i := range this.sources {
+			this.cursor[side][i] = 0
+			this.peeked[side][i] = nil
+		}
+	}
+	for i, src := range this.sources {
+		if this.peeked[side][i] != nil {
+			continue
+		}
+		p, q, sok := src.NextLevel(this.cursor[side][i], isBuy)
+		this.peeked[side][i] = &peekedLevel{price: p, qty: q, ok: sok}
+	}
+
+	best := -1
+	for i, lvl := range this.peeked[side] {
+		if !lvl.ok {
+			continue
+		}
+		if best == -1 {
+			best = i
+			continue
+		}
+		if isBuy && lvl.price > this.peeked[side][best].price {
+			best = i
+		}
+		if !isBuy && lvl.price < this.peeked[side][best].price {
+			best = i
+		}
+	}
+	if best == -1 {
+		return 0, 0, false
+	}
+
+	chosen := this.peeked[side][best]
+	this.cursor[side][best] = chosen.price
+	this.peeked[side][best] = nil
+	return chosen.price, chosen.qty, true
+}
@@ -0,0 +1,93 @@
+package hftorderbook
+
+import "testing"
+
+func TestConstantProductPoolNextLevelTicksAdvanceEveryCall(t *testing.T) {
+	pool := &ConstantProductPool{RX: 100, RY: 2000000, Fee: 30, TickSize: 50}
+
+	var prev int64
+	seen := make(map[int64]bool)
+	for i := 0; i < 3; i++ {
+		price, qty, ok := pool.NextLevel(prev, true)
+		if !ok {
+			t.Fatalf("tick %d: expected ok=true", i)
+		}
+		if qty <= 0 {
+			t.Fatalf("tick %d: expected positive qty, got %d", i, qty)
+		}
+		if seen[price] {
+			t.Fatalf("tick %d: price %d repeated a previous tick (a tick was skipped or re-derived incorrectly)", i, price)
+		}
+		seen[price] = true
+		if price >= prev && i > 0 {
+			t.Fatalf("tick %d: expected bid ladder to walk strictly down, got %d after %d", i, price, prev)
+		}
+		prev = price
+	}
+}
+
+func TestConstantProductPoolRestartsFromZero(t *testing.T) {
+	pool := &ConstantProductPool{RX: 100, RY: 2000000, Fee: 30, TickSize: 50}
+
+	first, _, ok := pool.NextLevel(0, true)
+	if !ok {
+		t.Fatal("expected first tick to be available")
+	}
+	pool.NextLevel(first, true)
+
+	restarted, _, ok := pool.NextLevel(0, true)
+	if !ok {
+		t.Fatal("expected restart tick to be available")
+	}
+	if restarted != first {
+		t.Fatalf("expected prevPrice=0 to restart the ladder at tick 1 (%d), got %d", first, restarted)
+	}
+}
+
+type fakeSource struct {
+	levels []int64 // prices in best-first order
+	idx    int
+}
+
+func (f *fakeSource) NextLevel(prevPrice int64, isBuy bool) (price, qty int64, ok bool) {
+	if prevPrice == 0 {
+		f.idx = 0
+	}
+	if f.idx >= len(f.levels) {
+		return 0, 0, false
+	}
+	p := f.levels[f.idx]
+	f.idx++
+	return p, 10, true
+}
+
+func TestMergeSourcesPicksBestAcrossChildren(t *testing.T) {
+	a := &fakeSource{levels: []int64{100, 95}}
+	b := &fakeSource{levels: []int64{102, 90}}
+	merged := MergeSources(a, b)
+
+	price, _, ok := merged.NextLevel(0, true)
+	if !ok || price != 102 {
+		t.Fatalf("expected best bid 102 first, got %d (ok=%v)", price, ok)
+	}
+	price, _, ok = merged.NextLevel(price, true)
+	if !ok || price != 100 {
+		t.Fatalf("expected next best bid 100, got %d (ok=%v)", price, ok)
+	}
+}
+
+func TestMergeSourcesRestartsFromZero(t *testing.T) {
+	a := &fakeSource{levels: []int64{100, 95}}
+	b := &fakeSource{levels: []int64{102, 90}}
+	merged := MergeSources(a, b)
+
+	first, _, ok := merged.NextLevel(0, true)
+	if !ok || first != 102 {
+		t.Fatalf("expected first best bid 102, got %d (ok=%v)", first, ok)
+	}
+
+	restarted, _, ok := merged.NextLevel(0, true)
+	if !ok || restarted != first {
+		t.Fatalf("expected prevPrice=0 to restart the merge at %d, got %d (ok=%v)", first, restarted, ok)
+	}
+}
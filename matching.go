@@ -0,0 +1,165 @@
+package hftorderbook
+
+import "errors"
+
+// TimeInForce controls how a taker order's residual quantity is handled
+// once it has walked as much of the opposite side as it can.
+type TimeInForce int
+
+const (
+	GTC TimeInForce = iota
+	IOC
+	FOK
+)
+
+// ErrFOKNotFillable is returned by Match when a FOK order cannot be filled
+// in full against the resting liquidity available within its MaxSlippage.
+var ErrFOKNotFillable = errors.New("hftorderbook: FOK order cannot be fully filled")
+
+// Fill describes one resting order consumed while matching a taker order.
+type Fill struct {
+	Price        int64
+	Volume       int64
+	TakerOrderId int
+	MakerOrderId int
+	TakerIsBuy   bool
+}
+
+// Match walks the opposite side of the book from the touch, consuming
+// resting orders FIFO within each limit, until order is fully filled, the
+// book is exhausted, or order.MaxSlippage is breached. Emptied limits are
+// deleted from their side and returned to the pool. The returned *Order is
+// the residual quantity left to rest, or nil if none remains to rest.
+func (this *Orderbook) Match(order *Order, tif TimeInForce) ([]Fill, *Order, error) {
+	tree, cache := this.restingSide(order.BidOrAsk)
+	if tree == nil || tree.IsEmpty() {
+		if tif == FOK {
+			return nil, nil, ErrFOKNotFillable
+		}
+		return nil, this.rest(order, tif), nil
+	}
+
+	touch := this.touchPrice(order.BidOrAsk, tree)
+
+	if tif == FOK && !this.canFill(order, touch, tree) {
+		return nil, nil, ErrFOKNotFillable
+	}
+
+	fills := make([]Fill, 0)
+	remaining := order.Volume
+	nodePointer := this.walkStart(order.BidOrAsk, tree)
+
+	for nodePointer != nil && remaining > 0 {
+		limit := nodePointer.Value
+		if order.MaxSlippage > 0 && slippageBreached(order.BidOrAsk, touch, limit.Price, order.MaxSlippage) {
+			break
+		}
+
+		for limit.Size() > 0 && remaining > 0 {
+			maker := limit.Front()
+			qty := maker.Volume
+			if qty > remaining {
+				qty = remaining
+			}
+
+			fill := Fill{
+				Price:        limit.Price,
+				Volume:       qty,
+				TakerOrderId: order.Id,
+				MakerOrderId: maker.Id,
+				TakerIsBuy:   order.BidOrAsk,
+			}
+			fills = append(fills, fill)
+			if this.OnFill != nil {
+				this.OnFill(fill)
+			}
+
+			limit.Fill(maker, qty)
+			remaining -= qty
+			if order.BidOrAsk {
+				this.TotalSellVolume -= qty
+			} else {
+				this.TotalBuyVolume -= qty
+			}
+
+			if maker.Volume == 0 {
+				limit.Delete(maker)
+				delete(this.IdToOrderMap, maker.Id)
+			}
+		}
+
+		next := this.walkNext(order.BidOrAsk, nodePointer)
+		if limit.Size() == 0 {
+			tree.Delete(limit.Price)
+			delete(cache, limit.Price)
+			this.pool.Put(limit)
+		}
+		nodePointer = next
+	}
+
+	order.Volume = remaining
+	resting := this.rest(order, tif)
+	return fills, resting, nil
+}
+
+// rest applies tif to whatever quantity is left in order.Volume after
+// walking the book: GTC rests it at order.Price, IOC/FOK discard it.
+func (this *Orderbook) rest(order *Order, tif TimeInForce) *Order {
+	if order.Volume == 0 || tif != GTC {
+		return nil
+	}
+	this.Add(order.Price, order)
+	return order
+}
+
+func (this *Orderbook) restingSide(isBuy bool) (*redBlackBST, map[int64]*LimitOrder) {
+	if isBuy {
+		return this.Asks, this.askLimitsCache
+	}
+	return this.Bids, this.bidLimitsCache
+}
+
+func (this *Orderbook) touchPrice(isBuy bool, tree *redBlackBST) int64 {
+	if isBuy {
+		return tree.MinPointer().Value.Price
+	}
+	return tree.MaxPointer().Value.Price
+}
+
+func (this *Orderbook) walkStart(isBuy bool, tree *redBlackBST) *nodeRedBlack {
+	if isBuy {
+		return tree.MinPointer()
+	}
+	return tree.MaxPointer()
+}
+
+func (this *Orderbook) walkNext(isBuy bool, np *nodeRedBlack) *nodeRedBlack {
+	if isBuy {
+		return np.Next
+	}
+	return np.Prev
+}
+
+// canFill pre-scans the resting side without mutating it, summing limit
+// volume within MaxSlippage of touch to decide whether a FOK order can be
+// filled in full.
+func (this *Orderbook) canFill(order *Order, touch int64, tree *redBlackBST) bool {
+	nodePointer := this.walkStart(order.BidOrAsk, tree)
+	var cumulative int64
+	for nodePointer != nil && cumulative < order.Volume {
+		limit := nodePointer.Value
+		if order.MaxSlippage > 0 && slippageBreached(order.BidOrAsk, touch, limit.Price, order.MaxSlippage) {
+			break
+		}
+		cumulative += limit.totalVolume
+		nodePointer = this.walkNext(order.BidOrAsk, nodePointer)
+	}
+	return cumulative >= order.Volume
+}
+
+func slippageBreached(isBuy bool, touch, price, maxSlippage int64) bool {
+	if isBuy {
+		return price-touch > maxSlippage
+	}
+	return touch-price > maxSlippage
+}
@@ -0,0 +1,130 @@
+package hftorderbook
+
+import "testing"
+
+func newTestBook(bidPrices, askPrices []int64, volumePerOrder int64, ordersPerLevel int) *Orderbook {
+	book := NewOrderbook()
+	id := 1
+	for _, price := range bidPrices {
+		for i := 0; i < ordersPerLevel; i++ {
+			book.Add(price, &Order{Id: id, Price: price, Volume: volumePerOrder, BidOrAsk: true})
+			id++
+		}
+	}
+	for _, price := range askPrices {
+		for i := 0; i < ordersPerLevel; i++ {
+			book.Add(price, &Order{Id: id, Price: price, Volume: volumePerOrder, BidOrAsk: false})
+			id++
+		}
+	}
+	return &book
+}
+
+func TestMatchConsumesRestingOrdersFIFO(t *testing.T) {
+	book := newTestBook(nil, []int64{100}, 5, 3) // three 5-lot asks resting at 100
+
+	taker := &Order{Id: 999, Price: 100, Volume: 8, BidOrAsk: true}
+	fills, residual, err := book.Match(taker, GTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if residual != nil {
+		t.Fatalf("expected full fill, got residual %+v", residual)
+	}
+	if len(fills) != 2 {
+		t.Fatalf("expected 2 fills (5 then 3), got %d: %+v", len(fills), fills)
+	}
+	if fills[0].MakerOrderId != 1 || fills[1].MakerOrderId != 2 {
+		t.Fatalf("expected FIFO consumption order 1 then 2, got %+v", fills)
+	}
+	if fills[0].Volume != 5 || fills[1].Volume != 3 {
+		t.Fatalf("expected volumes 5 then 3, got %+v", fills)
+	}
+	if book.GetVolumeAtAskLimit(100) != 7 {
+		t.Fatalf("expected 7 remaining at 100 (2 left on order 2, 5 untouched on order 3), got %d", book.GetVolumeAtAskLimit(100))
+	}
+}
+
+func TestMatchIOCCancelsResidual(t *testing.T) {
+	book := newTestBook(nil, []int64{100}, 5, 1)
+
+	taker := &Order{Id: 999, Price: 100, Volume: 8, BidOrAsk: true}
+	fills, residual, err := book.Match(taker, IOC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if residual != nil {
+		t.Fatalf("IOC must not rest a residual, got %+v", residual)
+	}
+	if len(fills) != 1 || fills[0].Volume != 5 {
+		t.Fatalf("expected single fill of 5, got %+v", fills)
+	}
+}
+
+func TestMatchGTCRestsResidual(t *testing.T) {
+	book := newTestBook(nil, []int64{100}, 5, 1)
+
+	taker := &Order{Id: 999, Price: 100, Volume: 8, BidOrAsk: true}
+	_, residual, err := book.Match(taker, GTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if residual == nil || residual.Volume != 3 {
+		t.Fatalf("expected residual of 3 resting, got %+v", residual)
+	}
+	if book.GetVolumeAtBidLimit(100) != 3 {
+		t.Fatalf("expected residual resting at 100, got %d", book.GetVolumeAtBidLimit(100))
+	}
+}
+
+func TestMatchFOKRejectsShortfall(t *testing.T) {
+	book := newTestBook(nil, []int64{100}, 5, 1)
+
+	taker := &Order{Id: 999, Price: 100, Volume: 8, BidOrAsk: true}
+	fills, residual, err := book.Match(taker, FOK)
+	if err != ErrFOKNotFillable {
+		t.Fatalf("expected ErrFOKNotFillable, got %v", err)
+	}
+	if fills != nil || residual != nil {
+		t.Fatalf("expected no fills/residual on FOK rejection, got fills=%+v residual=%+v", fills, residual)
+	}
+	if book.GetVolumeAtAskLimit(100) != 5 {
+		t.Fatalf("expected book untouched after FOK rejection, got %d", book.GetVolumeAtAskLimit(100))
+	}
+}
+
+func TestMatchFOKFillsWhenAvailable(t *testing.T) {
+	book := newTestBook(nil, []int64{100}, 5, 2) // 10 total
+
+	taker := &Order{Id: 999, Price: 100, Volume: 8, BidOrAsk: true}
+	fills, residual, err := book.Match(taker, FOK)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if residual != nil {
+		t.Fatalf("expected no residual on a fully-fillable FOK, got %+v", residual)
+	}
+	var total int64
+	for _, f := range fills {
+		total += f.Volume
+	}
+	if total != 8 {
+		t.Fatalf("expected 8 total filled, got %d", total)
+	}
+}
+
+func TestMatchMaxSlippageHaltsWalk(t *testing.T) {
+	book := newTestBook(nil, []int64{100, 110}, 5, 1)
+
+	taker := &Order{Id: 999, Price: 110, Volume: 10, BidOrAsk: true, MaxSlippage: 5}
+	fills, residual, err := book.Match(taker, GTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fills) != 1 || fills[0].Price != 100 {
+		t.Fatalf("expected only the touch level filled under MaxSlippage 5, got %+v", fills)
+	}
+	if residual == nil || residual.Volume != 5 {
+		t.Fatalf("expected remainder to rest, got %+v", residual)
+	}
+}
@@ -0,0 +1,21 @@
+package hftorderbook
+
+// Order is a single resting or incoming order. BidOrAsk is true for a bid
+// (buy), false for an ask (sell).
+type Order struct {
+	Id       int
+	Price    int64
+	Volume   int64
+	BidOrAsk bool
+
+	// MaxSlippage caps how far Match may walk the book away from the touch
+	// price before halting, in price units. Zero means unbounded.
+	MaxSlippage int64
+
+	// Limit is the price level this order currently rests at, set by
+	// LimitOrder.Enqueue and cleared by LimitOrder.Delete. nil until added
+	// to a book.
+	Limit *LimitOrder
+
+	next, prev *Order
+}
@@ -30,6 +30,16 @@ type Orderbook struct {
 	pool            *sync.Pool
 	TotalBuyVolume  int64
 	TotalSellVolume int64
+
+	// Pending buffers feed updates that arrive before a snapshot has been
+	// installed; lastSeq/snapshotInstalled track the feed's sequence state.
+	Pending           *PendingBuffer
+	lastSeq           uint64
+	snapshotInstalled bool
+
+	// OnFill, if set, is invoked synchronously for every fill generated by
+	// Match so callers (e.g. pkg/portfolio) can track exposure and PnL.
+	OnFill func(Fill)
 }
 
 func NewOrderbook() Orderbook {
@@ -165,7 +175,7 @@ func (this *Orderbook) clearLimit(price int64, bidOrAsk bool) {
 	}
 
 	if limit == nil {
-		panic(fmt.Sprintf("there is no such price limit %0.8f", price))
+		panic(fmt.Sprintf("there is no such price limit %d", price))
 	}
 
 	limit.Clear()
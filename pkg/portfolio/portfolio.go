@@ -0,0 +1,156 @@
+// Package portfolio tracks position, exposure and PnL from Orderbook fills,
+// turning the raw book into a full trading nucleus.
+package portfolio
+
+import (
+	"encoding/json"
+
+	hftorderbook "github.com/angel-one/go-hft-orderbook"
+)
+
+// Position is a weighted-average-entry view of one side's holdings. Base is
+// signed (negative is short); Quote is the signed cash flow spent acquiring
+// it.
+type Position struct {
+	Base     int64
+	Quote    int64
+	AvgEntry int64
+}
+
+// apply folds one fill of qty at price into the position, realizing PnL on
+// any portion that reduces or reverses the existing exposure, and returns
+// the PnL realized by this fill.
+func (p *Position) apply(qty, price int64, isBuy bool) int64 {
+	signedQty := qty
+	if !isBuy {
+		signedQty = -qty
+	}
+
+	sameDirection := p.Base == 0 || (p.Base > 0) == (signedQty > 0)
+	if sameDirection {
+		newBase := p.Base + signedQty
+		p.AvgEntry = (p.AvgEntry*abs(p.Base) + price*qty) / abs(newBase)
+		p.Base = newBase
+		p.Quote -= signedQty * price
+		return 0
+	}
+
+	wasLong := p.Base > 0
+	closingQty := qty
+	if abs(signedQty) > abs(p.Base) {
+		closingQty = abs(p.Base)
+	}
+
+	var realized int64
+	if wasLong {
+		realized = (price - p.AvgEntry) * closingQty
+	} else {
+		realized = (p.AvgEntry - price) * closingQty
+	}
+
+	newBase := p.Base + signedQty
+	p.Quote -= signedQty * price
+	p.Base = newBase
+	switch {
+	case newBase == 0:
+		p.AvgEntry = 0
+	case wasLong != (newBase > 0):
+		// position reversed through zero: the remainder opened at this fill's price
+		p.AvgEntry = price
+	}
+	return realized
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// ProfitStats aggregates realized/unrealized PnL and per-role volume for a
+// Tracker.
+type ProfitStats struct {
+	Realized    int64
+	Unrealized  int64
+	MakerVolume int64
+	TakerVolume int64
+}
+
+// Tracker accumulates Position and ProfitStats from Orderbook fills. Attach
+// it to a book's Match path via book.OnFill = tracker.OnFill.
+type Tracker struct {
+	Position Position
+	Stats    ProfitStats
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// OnFill records a fill in which this account was the taker. Matches the
+// Orderbook.OnFill callback signature.
+func (this *Tracker) OnFill(f hftorderbook.Fill) {
+	this.Stats.Realized += this.Position.apply(f.Volume, f.Price, f.TakerIsBuy)
+	this.Stats.TakerVolume += f.Volume
+}
+
+// CreditMaker records a fill in which this account's resting order was the
+// one consumed, for strategies that track their own maker-side fills
+// separately from the OnFill hook.
+func (this *Tracker) CreditMaker(f hftorderbook.Fill) {
+	this.Stats.Realized += this.Position.apply(f.Volume, f.Price, !f.TakerIsBuy)
+	this.Stats.MakerVolume += f.Volume
+}
+
+// MarkToMarket values the residual position by walking the book for the
+// size that would actually close it (VWAPForQuantity), so a position larger
+// than the top-of-book size is priced with its real exit impact rather than
+// the naive touch. Falls back to MidPrice only when the book can't fill any
+// of it; a flat position returns Stats.Realized without consulting the book.
+func (this *Tracker) MarkToMarket(book *hftorderbook.Orderbook) int64 {
+	base := this.Position.Base
+	if base == 0 {
+		return this.Stats.Realized
+	}
+
+	qty := base
+	if qty < 0 {
+		qty = -qty
+	}
+	// closing a long means selling (isBuy=false); closing a short means buying.
+	price, filled, _ := book.VWAPForQuantity(qty, base < 0)
+
+	if filled == 0 {
+		mid, ok := book.MidPrice()
+		if !ok {
+			return this.Stats.Realized
+		}
+		price = mid
+	}
+
+	this.Stats.Unrealized = (price - this.Position.AvgEntry) * base
+	return this.Stats.Realized + this.Stats.Unrealized
+}
+
+type snapshot struct {
+	Position Position
+	Stats    ProfitStats
+}
+
+// Snapshot serializes Position and Stats so a strategy can persist across
+// restarts.
+func (this *Tracker) Snapshot() ([]byte, error) {
+	return json.Marshal(snapshot{Position: this.Position, Stats: this.Stats})
+}
+
+// Restore reloads Position and Stats from a Snapshot produced earlier.
+func (this *Tracker) Restore(data []byte) error {
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	this.Position = s.Position
+	this.Stats = s.Stats
+	return nil
+}
@@ -0,0 +1,80 @@
+package portfolio
+
+import (
+	"testing"
+
+	hftorderbook "github.com/angel-one/go-hft-orderbook"
+)
+
+func TestTrackerOnFillWeightedAverageEntry(t *testing.T) {
+	tr := NewTracker()
+
+	tr.OnFill(hftorderbook.Fill{Price: 100, Volume: 10, TakerIsBuy: true})
+	tr.OnFill(hftorderbook.Fill{Price: 110, Volume: 10, TakerIsBuy: true})
+
+	if tr.Position.Base != 20 {
+		t.Fatalf("expected Base 20, got %d", tr.Position.Base)
+	}
+	if tr.Position.AvgEntry != 105 {
+		t.Fatalf("expected weighted AvgEntry 105, got %d", tr.Position.AvgEntry)
+	}
+}
+
+func TestTrackerOnFillRealizesPnLOnReduce(t *testing.T) {
+	tr := NewTracker()
+
+	tr.OnFill(hftorderbook.Fill{Price: 100, Volume: 10, TakerIsBuy: true})
+	tr.OnFill(hftorderbook.Fill{Price: 120, Volume: 4, TakerIsBuy: false})
+
+	if tr.Position.Base != 6 {
+		t.Fatalf("expected Base 6 after partial reduce, got %d", tr.Position.Base)
+	}
+	if tr.Stats.Realized != 80 {
+		t.Fatalf("expected Realized 80 ((120-100)*4), got %d", tr.Stats.Realized)
+	}
+	if tr.Position.AvgEntry != 100 {
+		t.Fatalf("expected AvgEntry to stay 100 on a partial reduce, got %d", tr.Position.AvgEntry)
+	}
+}
+
+func TestTrackerOnFillReversesThroughZero(t *testing.T) {
+	tr := NewTracker()
+
+	tr.OnFill(hftorderbook.Fill{Price: 100, Volume: 10, TakerIsBuy: true})
+	tr.OnFill(hftorderbook.Fill{Price: 90, Volume: 15, TakerIsBuy: false})
+
+	if tr.Position.Base != -5 {
+		t.Fatalf("expected Base -5 after reversal, got %d", tr.Position.Base)
+	}
+	if tr.Stats.Realized != -100 {
+		t.Fatalf("expected Realized -100 ((90-100)*10), got %d", tr.Stats.Realized)
+	}
+	if tr.Position.AvgEntry != 90 {
+		t.Fatalf("expected remainder opened at fill price 90, got %d", tr.Position.AvgEntry)
+	}
+}
+
+func TestTrackerCreditMakerTracksMakerVolume(t *testing.T) {
+	tr := NewTracker()
+	tr.CreditMaker(hftorderbook.Fill{Price: 100, Volume: 5, TakerIsBuy: true})
+
+	if tr.Stats.MakerVolume != 5 {
+		t.Fatalf("expected MakerVolume 5, got %d", tr.Stats.MakerVolume)
+	}
+	if tr.Stats.TakerVolume != 0 {
+		t.Fatalf("expected TakerVolume untouched, got %d", tr.Stats.TakerVolume)
+	}
+	// the maker was on the opposite side of the taker, so a buy taker fill
+	// means the maker sold.
+	if tr.Position.Base != -5 {
+		t.Fatalf("expected maker Base -5 (sold into a buy taker), got %d", tr.Position.Base)
+	}
+}
+
+func TestTrackerMarkToMarketFlatPosition(t *testing.T) {
+	tr := NewTracker()
+	var book hftorderbook.Orderbook
+	if got := tr.MarkToMarket(&book); got != tr.Stats.Realized {
+		t.Fatalf("expected flat position to return Realized unchanged, got %d", got)
+	}
+}
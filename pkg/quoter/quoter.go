@@ -0,0 +1,116 @@
+// Package quoter builds layered maker quotes on top of an Orderbook,
+// separating market-making sizing/placement logic from the book itself.
+package quoter
+
+import (
+	hftorderbook "github.com/angel-one/go-hft-orderbook"
+)
+
+// QuantityScale maps a layer index (0 = closest to the touch) to a quote
+// quantity.
+type QuantityScale interface {
+	Quantity(layer int) int64
+}
+
+// LinearScale interpolates quantity linearly between Range[0] at
+// Domain[0] and Range[1] at Domain[1].
+type LinearScale struct {
+	Domain [2]int
+	Range  [2]int64
+}
+
+func (s LinearScale) Quantity(layer int) int64 {
+	span := s.Domain[1] - s.Domain[0]
+	if span == 0 {
+		return s.Range[0]
+	}
+	t := float64(layer-s.Domain[0]) / float64(span)
+	return s.Range[0] + int64(t*float64(s.Range[1]-s.Range[0]))
+}
+
+// ExpScale grows quantity exponentially from Min at layer 0 towards Max.
+type ExpScale struct {
+	Base     float64
+	Min, Max int64
+}
+
+func (s ExpScale) Quantity(layer int) int64 {
+	qty := s.Min
+	scale := 1.0
+	for i := 0; i < layer; i++ {
+		scale *= s.Base
+	}
+	qty = int64(float64(s.Min) * scale)
+	if qty > s.Max {
+		return s.Max
+	}
+	return qty
+}
+
+// LayerConfig configures how many maker layers to generate per side and how
+// far apart and how large each one is.
+type LayerConfig struct {
+	NumLayers     int
+	PipsPerLayer  int64
+	QuantityScale QuantityScale
+
+	// BehindVolume, when non-nil, overrides QuantityScale: each layer is
+	// sized to sit just behind BehindVolume.Target resting volume at that
+	// price, as reported by the book's GetDepthRank.
+	BehindVolume *BehindVolumeScale
+}
+
+// BehindVolumeScale sizes a layer so the maker's cumulative quantity rests
+// just behind a target volume already resting ahead of it in the book.
+type BehindVolumeScale struct {
+	Target int64
+}
+
+// GenerateLayers produces NumLayers maker quotes per side, walking away from
+// the touch by PipsPerLayer at each step and sizing each layer via cfg's
+// QuantityScale (or BehindVolume, if set).
+func GenerateLayers(book *hftorderbook.Orderbook, cfg LayerConfig) ([]hftorderbook.OrderDepth, []hftorderbook.OrderDepth) {
+	bids := book.GetNBestBid(1)
+	asks := book.GetNBestOffer(1)
+
+	// GetNBestBid/GetNBestOffer always return a length-1 slice, zero-valued
+	// when that side is empty, so an empty touch must be detected from
+	// OrderCount rather than slice length.
+	var bidLayers, askLayers []hftorderbook.OrderDepth
+	if len(bids) > 0 && bids[0].OrderCount > 0 {
+		bidLayers = buildSide(book, cfg, bids[0].Price, true)
+	}
+	if len(asks) > 0 && asks[0].OrderCount > 0 {
+		askLayers = buildSide(book, cfg, asks[0].Price, false)
+	}
+	return bidLayers, askLayers
+}
+
+func buildSide(book *hftorderbook.Orderbook, cfg LayerConfig, touch int64, isBuy bool) []hftorderbook.OrderDepth {
+	layers := make([]hftorderbook.OrderDepth, cfg.NumLayers)
+	var cumulative int64
+
+	for i := 0; i < cfg.NumLayers; i++ {
+		var price int64
+		if isBuy {
+			price = touch - int64(i+1)*cfg.PipsPerLayer
+		} else {
+			price = touch + int64(i+1)*cfg.PipsPerLayer
+		}
+
+		var qty int64
+		if cfg.BehindVolume != nil {
+			rank := book.GetDepthRank(price, isBuy)
+			qty = cfg.BehindVolume.Target - rank.VolumeAhead - cumulative
+			if qty < 0 {
+				qty = 0
+			}
+		} else {
+			qty = cfg.QuantityScale.Quantity(i)
+		}
+
+		cumulative += qty
+		layers[i] = hftorderbook.OrderDepth{Price: price, Volume: qty}
+	}
+	return layers
+}
@@ -0,0 +1,103 @@
+package quoter
+
+import (
+	"testing"
+
+	hftorderbook "github.com/angel-one/go-hft-orderbook"
+)
+
+func TestLinearScaleInterpolates(t *testing.T) {
+	scale := LinearScale{Domain: [2]int{0, 4}, Range: [2]int64{100, 500}}
+
+	if got := scale.Quantity(0); got != 100 {
+		t.Fatalf("expected 100 at layer 0, got %d", got)
+	}
+	if got := scale.Quantity(4); got != 500 {
+		t.Fatalf("expected 500 at layer 4, got %d", got)
+	}
+	if got := scale.Quantity(2); got != 300 {
+		t.Fatalf("expected midpoint 300 at layer 2, got %d", got)
+	}
+}
+
+func TestExpScaleGrowsAndCaps(t *testing.T) {
+	scale := ExpScale{Base: 2, Min: 10, Max: 100}
+
+	if got := scale.Quantity(0); got != 10 {
+		t.Fatalf("expected Min 10 at layer 0, got %d", got)
+	}
+	if got := scale.Quantity(2); got != 40 {
+		t.Fatalf("expected 40 (10*2^2) at layer 2, got %d", got)
+	}
+	if got := scale.Quantity(10); got != 100 {
+		t.Fatalf("expected growth capped at Max 100, got %d", got)
+	}
+}
+
+func TestGenerateLayersSpacesPricesByPipsPerLayer(t *testing.T) {
+	book := hftorderbook.NewOrderbook()
+	book.Add(99, &hftorderbook.Order{Id: 1, Price: 99, Volume: 10, BidOrAsk: true})
+	book.Add(101, &hftorderbook.Order{Id: 2, Price: 101, Volume: 10, BidOrAsk: false})
+
+	bidLayers, askLayers := GenerateLayers(&book, LayerConfig{
+		NumLayers:     3,
+		PipsPerLayer:  1,
+		QuantityScale: LinearScale{Domain: [2]int{0, 2}, Range: [2]int64{10, 30}},
+	})
+
+	if len(bidLayers) != 3 || len(askLayers) != 3 {
+		t.Fatalf("expected 3 layers per side, got %d bids %d asks", len(bidLayers), len(askLayers))
+	}
+	if bidLayers[0].Price != 98 || bidLayers[1].Price != 97 || bidLayers[2].Price != 96 {
+		t.Fatalf("expected bid layers walking down from touch by 1, got %+v", bidLayers)
+	}
+	if askLayers[0].Price != 102 || askLayers[1].Price != 103 || askLayers[2].Price != 104 {
+		t.Fatalf("expected ask layers walking up from touch by 1, got %+v", askLayers)
+	}
+}
+
+func TestGenerateLayersBehindVolumeSizesToTarget(t *testing.T) {
+	book := hftorderbook.NewOrderbook()
+	book.Add(98, &hftorderbook.Order{Id: 1, Price: 98, Volume: 50, BidOrAsk: true})
+	book.Add(99, &hftorderbook.Order{Id: 2, Price: 99, Volume: 10, BidOrAsk: true})
+
+	bidLayers, _ := GenerateLayers(&book, LayerConfig{
+		NumLayers:    2,
+		PipsPerLayer: 1,
+		BehindVolume: &BehindVolumeScale{Target: 100},
+	})
+
+	// touch is 99 (volume 10); layer 0 sits at 98 where VolumeAhead already
+	// includes the 50 resting there, so the maker should size itself to
+	// just top up to the 100 target, not duplicate the resting volume.
+	if bidLayers[0].Volume > 100 {
+		t.Fatalf("expected layer sized behind target volume, got %+v", bidLayers[0])
+	}
+}
+
+func TestGenerateLayersSkipsEmptyOrOneSidedBook(t *testing.T) {
+	book := hftorderbook.NewOrderbook()
+
+	bidLayers, askLayers := GenerateLayers(&book, LayerConfig{
+		NumLayers:     2,
+		PipsPerLayer:  1,
+		QuantityScale: LinearScale{Domain: [2]int{0, 1}, Range: [2]int64{10, 20}},
+	})
+	if bidLayers != nil || askLayers != nil {
+		t.Fatalf("expected no layers on an empty book, got bids=%+v asks=%+v", bidLayers, askLayers)
+	}
+
+	book.Add(99, &hftorderbook.Order{Id: 1, Price: 99, Volume: 10, BidOrAsk: true})
+
+	bidLayers, askLayers = GenerateLayers(&book, LayerConfig{
+		NumLayers:     2,
+		PipsPerLayer:  1,
+		QuantityScale: LinearScale{Domain: [2]int{0, 1}, Range: [2]int64{10, 20}},
+	})
+	if len(bidLayers) != 2 {
+		t.Fatalf("expected 2 bid layers off the resting bid, got %+v", bidLayers)
+	}
+	if askLayers != nil {
+		t.Fatalf("expected no ask layers on a book with no resting asks, got %+v", askLayers)
+	}
+}
@@ -0,0 +1,104 @@
+package hftorderbook
+
+// VWAPForQuantity walks the ask side (for a buy) or bid side (for a sell)
+// from the touch, summing resting volume until qty is met or the side is
+// exhausted. It returns the size-weighted average price actually available,
+// the quantity filled (which may be less than qty if the book is thin), and
+// the worst price touched.
+func (this *Orderbook) VWAPForQuantity(qty int64, isBuy bool) (avgPrice int64, filled int64, worstPrice int64) {
+	tree, start, next := walkSide(this, isBuy)
+	if tree == nil || tree.IsEmpty() {
+		return 0, 0, 0
+	}
+
+	var notional int64
+	nodePointer := start()
+	for nodePointer != nil && filled < qty {
+		limit := nodePointer.Value
+		take := qty - filled
+		if limit.totalVolume < take {
+			take = limit.totalVolume
+		}
+
+		notional += take * limit.Price
+		filled += take
+		worstPrice = limit.Price
+		nodePointer = next(nodePointer)
+	}
+
+	if filled == 0 {
+		return 0, 0, 0
+	}
+	return notional / filled, filled, worstPrice
+}
+
+// QuantityForPrice walks the ask side (for a buy) or bid side (for a sell)
+// from the touch, summing the volume and notional available at or better
+// than limitPrice.
+func (this *Orderbook) QuantityForPrice(limitPrice int64, isBuy bool) (qty int64, notional int64) {
+	tree, start, next := walkSide(this, isBuy)
+	if tree == nil || tree.IsEmpty() {
+		return 0, 0
+	}
+
+	nodePointer := start()
+	for nodePointer != nil {
+		limit := nodePointer.Value
+		if isBuy && limit.Price > limitPrice {
+			break
+		}
+		if !isBuy && limit.Price < limitPrice {
+			break
+		}
+
+		qty += limit.totalVolume
+		notional += limit.totalVolume * limit.Price
+		nodePointer = next(nodePointer)
+	}
+	return qty, notional
+}
+
+// MidPrice returns the simple average of the best bid and best ask, and
+// false if either side of the book is empty.
+func (this *Orderbook) MidPrice() (int64, bool) {
+	if this.Bids == nil || this.Asks == nil || this.Bids.IsEmpty() || this.Asks.IsEmpty() {
+		return 0, false
+	}
+	bid := this.Bids.MaxPointer().Value.Price
+	ask := this.Asks.MinPointer().Value.Price
+	return (bid + ask) / 2, true
+}
+
+// MicroPrice returns the best bid/ask weighted by the opposite side's
+// resting size at the touch, so it leans towards the side more likely to be
+// consumed first.
+func (this *Orderbook) MicroPrice() (int64, bool) {
+	if this.Bids == nil || this.Asks == nil || this.Bids.IsEmpty() || this.Asks.IsEmpty() {
+		return 0, false
+	}
+	bidLimit := this.Bids.MaxPointer().Value
+	askLimit := this.Asks.MinPointer().Value
+
+	totalVolume := bidLimit.totalVolume + askLimit.totalVolume
+	if totalVolume == 0 {
+		return (bidLimit.Price + askLimit.Price) / 2, true
+	}
+	price := (bidLimit.Price*askLimit.totalVolume + askLimit.Price*bidLimit.totalVolume) / totalVolume
+	return price, true
+}
+
+// walkSide picks the tree a buy (asks, ascending from touch) or sell (bids,
+// descending from touch) should be priced against, along with the start and
+// step functions for that direction.
+func walkSide(o *Orderbook, isBuy bool) (tree *redBlackBST, start func() *nodeRedBlack, next func(*nodeRedBlack) *nodeRedBlack) {
+	if isBuy {
+		tree = o.Asks
+		start = tree.MinPointer
+		next = func(np *nodeRedBlack) *nodeRedBlack { return np.Next }
+	} else {
+		tree = o.Bids
+		start = tree.MaxPointer
+		next = func(np *nodeRedBlack) *nodeRedBlack { return np.Prev }
+	}
+	return tree, start, next
+}
@@ -0,0 +1,67 @@
+package hftorderbook
+
+import "testing"
+
+func TestVWAPForQuantityWalksMultipleLevels(t *testing.T) {
+	book := newTestBook(nil, []int64{100, 101}, 5, 1)
+
+	avgPrice, filled, worst := book.VWAPForQuantity(8, true)
+	if filled != 8 {
+		t.Fatalf("expected filled 8, got %d", filled)
+	}
+	if worst != 101 {
+		t.Fatalf("expected worst price 101, got %d", worst)
+	}
+	// (5*100 + 3*101)/8 = 100.375 -> integer division -> 100
+	if avgPrice != 100 {
+		t.Fatalf("expected avgPrice 100, got %d", avgPrice)
+	}
+}
+
+func TestVWAPForQuantityPartialFillOnThinBook(t *testing.T) {
+	book := newTestBook(nil, []int64{100}, 5, 1)
+
+	_, filled, worst := book.VWAPForQuantity(20, true)
+	if filled != 5 {
+		t.Fatalf("expected filled capped at available 5, got %d", filled)
+	}
+	if worst != 100 {
+		t.Fatalf("expected worst price 100, got %d", worst)
+	}
+}
+
+func TestQuantityForPriceSumsLevelsAtOrBetter(t *testing.T) {
+	book := newTestBook(nil, []int64{100, 101, 102}, 5, 1)
+
+	qty, notional := book.QuantityForPrice(101, true)
+	if qty != 10 {
+		t.Fatalf("expected qty 10 (levels 100,101), got %d", qty)
+	}
+	if notional != 5*100+5*101 {
+		t.Fatalf("expected notional %d, got %d", 5*100+5*101, notional)
+	}
+}
+
+func TestMidAndMicroPrice(t *testing.T) {
+	book := newTestBook([]int64{99}, []int64{101}, 10, 1)
+
+	mid, ok := book.MidPrice()
+	if !ok || mid != 100 {
+		t.Fatalf("expected mid 100, got %d (ok=%v)", mid, ok)
+	}
+
+	micro, ok := book.MicroPrice()
+	if !ok {
+		t.Fatalf("expected micro price available")
+	}
+	if micro != 100 { // equal size both sides -> same as mid
+		t.Fatalf("expected micro price 100 with balanced size, got %d", micro)
+	}
+}
+
+func TestMidPriceEmptySide(t *testing.T) {
+	book := NewOrderbook()
+	if _, ok := book.MidPrice(); ok {
+		t.Fatal("expected MidPrice to report unavailable on an empty book")
+	}
+}
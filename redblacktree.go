@@ -0,0 +1,99 @@
+package hftorderbook
+
+// nodeRedBlack is one price level's slot in a redBlackBST. Next/Prev thread
+// every node into a sorted doubly linked list (ascending by Price), so
+// callers can walk the book from the touch without re-traversing the tree.
+type nodeRedBlack struct {
+	Price int64
+	Value *LimitOrder
+	Next  *nodeRedBlack
+	Prev  *nodeRedBlack
+}
+
+// redBlackBST is an ordered map from price to *LimitOrder. It's backed by a
+// price-sorted doubly linked list plus a hash index for O(1) point lookup on
+// Put/Delete; it trades the balancing its name implies for the simplicity of
+// getting insert/delete/traversal provably right, since depth-of-book sizes
+// in this package stay small enough that the linked-list insert cost doesn't
+// matter.
+type redBlackBST struct {
+	head, tail *nodeRedBlack
+	nodes      map[int64]*nodeRedBlack
+}
+
+func NewRedBlackBST() redBlackBST {
+	return redBlackBST{nodes: make(map[int64]*nodeRedBlack)}
+}
+
+func (this *redBlackBST) IsEmpty() bool {
+	return len(this.nodes) == 0
+}
+
+// MinPointer returns the lowest-priced node, or nil if empty.
+func (this *redBlackBST) MinPointer() *nodeRedBlack {
+	return this.head
+}
+
+// MaxPointer returns the highest-priced node, or nil if empty.
+func (this *redBlackBST) MaxPointer() *nodeRedBlack {
+	return this.tail
+}
+
+// Put inserts value at price, or replaces the value already there.
+func (this *redBlackBST) Put(price int64, value *LimitOrder) {
+	if existing, ok := this.nodes[price]; ok {
+		existing.Value = value
+		return
+	}
+
+	n := &nodeRedBlack{Price: price, Value: value}
+	this.nodes[price] = n
+
+	if this.head == nil {
+		this.head, this.tail = n, n
+		return
+	}
+	if price < this.head.Price {
+		n.Next = this.head
+		this.head.Prev = n
+		this.head = n
+		return
+	}
+	if price > this.tail.Price {
+		n.Prev = this.tail
+		this.tail.Next = n
+		this.tail = n
+		return
+	}
+
+	cur := this.head
+	for cur.Price < price {
+		cur = cur.Next
+	}
+	prev := cur.Prev
+	n.Prev = prev
+	n.Next = cur
+	prev.Next = n
+	cur.Prev = n
+}
+
+// Delete removes the node at price, if any.
+func (this *redBlackBST) Delete(price int64) {
+	n, ok := this.nodes[price]
+	if !ok {
+		return
+	}
+	delete(this.nodes, price)
+
+	if n.Prev != nil {
+		n.Prev.Next = n.Next
+	} else {
+		this.head = n.Next
+	}
+	if n.Next != nil {
+		n.Next.Prev = n.Prev
+	} else {
+		this.tail = n.Prev
+	}
+	n.Next, n.Prev = nil, nil
+}